@@ -1,6 +1,9 @@
 package boxlayout
 
-import "math"
+import (
+	"math"
+	"sort"
+)
 
 type Dimensions struct {
 	X0 int
@@ -14,6 +17,25 @@ const (
 	COLUMN
 )
 
+// Alignment decides where leftover space goes in a box with no weighted children, i.e. one whose
+// Size/Percent children don't fill the available space.
+const (
+	START = iota
+	END
+	CENTER
+)
+
+// Anchor decides where an overlay box is positioned within its parent's rectangle.
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTopRight
+	AnchorBottomLeft
+	AnchorBottomRight
+	AnchorCenter
+)
+
 // to give a high-level explanation of what's going on here. We layout our views by arranging a bunch of boxes in the window.
 // If a box has children, it needs to specify how it wants to arrange those children: ROW or COLUMN.
 // If a box represents a view, you can put the view name in the viewName field.
@@ -45,6 +67,48 @@ type Box struct {
 	// dynamic size. Once all statically sized children have been considered, Weight decides how much of the remaining space will be taken up by the box
 	// TODO: consider making there be one int and a type enum so we can't have size and Weight simultaneously defined
 	Weight int
+
+	// Percent reserves a fixed percentage (0-100) of the parent's available space for this box,
+	// evaluated after static Size children are subtracted but before Weight is apportioned. A box
+	// must set at most one of Size, Weight and Percent; ArrangeViews panics otherwise.
+	Percent int
+
+	// MinSize and MaxSize clamp the size apportioned to a weighted box. A zero value means the
+	// corresponding bound is not enforced. These have no effect on boxes with a static Size.
+	MinSize int
+	MaxSize int
+
+	// Gap is the number of rows/columns of space to leave between this box's children.
+	Gap int
+
+	// Padding is the number of rows/columns of space to leave between this box's own edges and its children.
+	Padding int
+
+	// Alignment decides where leftover space goes when this box has no weighted children to soak
+	// it up, i.e. its children are sized by Size/Percent alone and don't fill the available space.
+	// Defaults to START, which is the previous behaviour of leaving the leftover space after the
+	// last child.
+	Alignment int
+
+	// OverlayChildren are positioned within this box's rectangle independently of Children, after
+	// the normal layout has already been computed. This lets transient views (menus, confirmation
+	// prompts, notifications) be shown without disturbing the rest of the layout.
+	OverlayChildren []*Box
+
+	// Anchor controls where this box is placed within its parent when it appears in the parent's
+	// OverlayChildren. Ignored otherwise.
+	Anchor Anchor
+
+	// Width and Height give an overlay box's size: the dimensions it's positioned or packed with.
+	// Ignored for boxes that aren't overlays.
+	Width  int
+	Height int
+
+	// Packed puts this overlay through the shelf-packing pass instead of positioning it via
+	// Anchor, so that several of them (e.g. simultaneous notifications) lay out left-to-right in
+	// rows, wrapping onto a new row once one runs out of width, instead of overlapping each other
+	// at the same anchor point. Ignored for boxes that aren't overlays.
+	Packed bool
 }
 
 func ArrangeViews(root *Box, x0, y0, width, height int) map[string]Dimensions {
@@ -53,11 +117,21 @@ func ArrangeViews(root *Box, x0, y0, width, height int) map[string]Dimensions {
 		// leaf node
 		if root.ViewName != "" {
 			dimensionsForView := Dimensions{X0: x0, Y0: y0, X1: x0 + width - 1, Y1: y0 + height - 1}
-			return map[string]Dimensions{root.ViewName: dimensionsForView}
+			result := map[string]Dimensions{root.ViewName: dimensionsForView}
+			return mergeDimensionMaps(result, arrangeOverlays(root.OverlayChildren, x0, y0, width, height))
 		}
-		return map[string]Dimensions{}
+		return arrangeOverlays(root.OverlayChildren, x0, y0, width, height)
 	}
 
+	// overlays are positioned within the box's full rectangle, not the padded area
+	outerX0, outerY0, outerWidth, outerHeight := x0, y0, width, height
+
+	// apply padding: shrink the area available to children and offset it inward
+	x0 += root.Padding
+	y0 += root.Padding
+	width -= 2 * root.Padding
+	height -= 2 * root.Padding
+
 	direction := root.getDirection(width, height)
 
 	var availableSize int
@@ -67,13 +141,25 @@ func ArrangeViews(root *Box, x0, y0, width, height int) map[string]Dimensions {
 		availableSize = height
 	}
 
+	// leave room for a gap between adjacent children
+	if len(children) > 1 {
+		availableSize -= root.Gap * (len(children) - 1)
+	}
+
 	// work out size taken up by children
 	reservedSize := 0
-	totalWeight := 0
+	var percentChildren []*Box
+	var weightedChildren []*Box
 	for _, child := range children {
-		// assuming either size or weight are non-zero
-		reservedSize += child.Size
-		totalWeight += child.Weight
+		child.validateSizeSpec()
+		switch {
+		case child.isStatic():
+			reservedSize += child.Size
+		case child.Percent > 0:
+			percentChildren = append(percentChildren, child)
+		default:
+			weightedChildren = append(weightedChildren, child)
+		}
 	}
 
 	remainingSize := availableSize - reservedSize
@@ -81,25 +167,43 @@ func ArrangeViews(root *Box, x0, y0, width, height int) map[string]Dimensions {
 		remainingSize = 0
 	}
 
-	unitSize := 0
-	extraSize := 0
-	if totalWeight > 0 {
-		unitSize = remainingSize / totalWeight
-		extraSize = remainingSize % totalWeight
+	// percentages are a fraction of what's left after static children, and come out before weights are apportioned
+	percentBase := remainingSize
+	percentSizes := map[*Box]int{}
+	for _, child := range percentChildren {
+		percentSize := percentBase * child.Percent / 100
+		percentSizes[child] = percentSize
+		remainingSize -= percentSize
+	}
+	if remainingSize < 0 {
+		remainingSize = 0
+	}
+
+	boxSizes := apportionWeightedSizes(weightedChildren, remainingSize)
+
+	// with no weighted children to soak up the remaining space, Alignment decides where it goes
+	leftoverSize := 0
+	if len(weightedChildren) == 0 {
+		leftoverSize = remainingSize
 	}
 
 	result := map[string]Dimensions{}
 	offset := 0
-	for _, child := range children {
+	switch root.Alignment {
+	case END:
+		offset = leftoverSize
+	case CENTER:
+		offset = leftoverSize / 2
+	}
+	for i, child := range children {
 		var boxSize int
-		if child.isStatic() {
+		switch {
+		case child.isStatic():
 			boxSize = child.Size
-		} else {
-			// TODO: consider more evenly distributing the remainder
-			boxSize = unitSize * child.Weight
-			boxExtraSize := int(math.Min(float64(extraSize), float64(child.Weight)))
-			boxSize += boxExtraSize
-			extraSize -= boxExtraSize
+		case child.Percent > 0:
+			boxSize = percentSizes[child]
+		default:
+			boxSize = boxSizes[child]
 		}
 
 		var resultForChild map[string]Dimensions
@@ -111,6 +215,153 @@ func ArrangeViews(root *Box, x0, y0, width, height int) map[string]Dimensions {
 
 		result = mergeDimensionMaps(result, resultForChild)
 		offset += boxSize
+		if i < len(children)-1 {
+			offset += root.Gap
+		}
+	}
+
+	return mergeDimensionMaps(result, arrangeOverlays(root.OverlayChildren, outerX0, outerY0, outerWidth, outerHeight))
+}
+
+// arrangeOverlays positions each overlay independently within the rectangle described by
+// x0, y0, width, height, rather than sharing that space the way Children do. Overlays are placed
+// according to their Anchor, except those marked Packed, which go through a shelf-packing pass
+// instead so that several of them can be shown at once without overlapping.
+func arrangeOverlays(overlays []*Box, x0, y0, width, height int) map[string]Dimensions {
+	result := map[string]Dimensions{}
+	if len(overlays) == 0 {
+		return result
+	}
+
+	var anchored, packed []*Box
+	for _, overlay := range overlays {
+		if overlay.Packed {
+			packed = append(packed, overlay)
+		} else {
+			anchored = append(anchored, overlay)
+		}
+	}
+
+	for _, overlay := range anchored {
+		ox, oy := anchorPosition(overlay.Anchor, x0, y0, width, height, overlay.Width, overlay.Height)
+		result = mergeDimensionMaps(result, ArrangeViews(overlay, ox, oy, overlay.Width, overlay.Height))
+	}
+
+	return mergeDimensionMaps(result, packOverlaysIntoShelves(packed, x0, y0, width, height))
+}
+
+func anchorPosition(anchor Anchor, x0, y0, width, height, boxWidth, boxHeight int) (int, int) {
+	x, y := x0, y0
+	switch anchor {
+	case AnchorTopRight:
+		x = x0 + width - boxWidth
+	case AnchorBottomLeft:
+		y = y0 + height - boxHeight
+	case AnchorBottomRight:
+		x = x0 + width - boxWidth
+		y = y0 + height - boxHeight
+	case AnchorCenter:
+		x = x0 + (width-boxWidth)/2
+		y = y0 + (height-boxHeight)/2
+	}
+	return x, y
+}
+
+// packOverlaysIntoShelves places overlays left-to-right in rows, starting a new row whenever the
+// current row runs out of width, so that several simultaneous Packed overlays (e.g. stacked
+// notifications) don't end up drawn on top of one another.
+func packOverlaysIntoShelves(overlays []*Box, x0, y0, width, height int) map[string]Dimensions {
+	result := map[string]Dimensions{}
+	if len(overlays) == 0 {
+		return result
+	}
+
+	sorted := make([]*Box, len(overlays))
+	copy(sorted, overlays)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Height > sorted[j].Height })
+
+	x, y, rowHeight := x0, y0, 0
+	for _, overlay := range sorted {
+		if x > x0 && x+overlay.Width > x0+width {
+			x = x0
+			y += rowHeight
+			rowHeight = 0
+		}
+
+		result = mergeDimensionMaps(result, ArrangeViews(overlay, x, y, overlay.Width, overlay.Height))
+
+		x += overlay.Width
+		if overlay.Height > rowHeight {
+			rowHeight = overlay.Height
+		}
+	}
+
+	return result
+}
+
+// apportionWeightedSizes divvies up remainingSize across the given weighted boxes, honouring each
+// box's MinSize/MaxSize. Boxes that get clamped are removed from the weighted pool and the
+// distribution is re-run on the rest, so that satisfying one box's bounds doesn't starve the
+// others of their fair share. If remainingSize is exhausted before every box is satisfied, the
+// unsatisfied boxes just get their MinSize and the container overflows gracefully.
+func apportionWeightedSizes(boxes []*Box, remainingSize int) map[*Box]int {
+	result := map[*Box]int{}
+
+	active := boxes
+	for len(active) > 0 {
+		if remainingSize <= 0 {
+			for _, child := range active {
+				result[child] = child.MinSize
+			}
+			break
+		}
+
+		totalWeight := 0
+		for _, child := range active {
+			totalWeight += child.Weight
+		}
+		if totalWeight == 0 {
+			break
+		}
+
+		unitSize := remainingSize / totalWeight
+		extraSize := remainingSize % totalWeight
+
+		var stillActive []*Box
+		clampedAny := false
+		for _, child := range active {
+			// TODO: consider more evenly distributing the remainder
+			boxSize := unitSize * child.Weight
+			boxExtraSize := int(math.Min(float64(extraSize), float64(child.Weight)))
+			boxSize += boxExtraSize
+			extraSize -= boxExtraSize
+
+			clampedSize := boxSize
+			if child.MinSize > 0 && clampedSize < child.MinSize {
+				clampedSize = child.MinSize
+			}
+			if child.MaxSize > 0 && clampedSize > child.MaxSize {
+				clampedSize = child.MaxSize
+			}
+
+			if clampedSize != boxSize {
+				result[child] = clampedSize
+				remainingSize -= clampedSize
+				clampedAny = true
+			} else {
+				result[child] = boxSize
+				stillActive = append(stillActive, child)
+			}
+		}
+
+		if !clampedAny {
+			break
+		}
+
+		if remainingSize < 0 {
+			remainingSize = 0
+		}
+		active = stillActive
 	}
 
 	return result
@@ -120,6 +371,24 @@ func (b *Box) isStatic() bool {
 	return b.Size > 0
 }
 
+// validateSizeSpec panics if a box sets more than one of Size, Weight and Percent: they're
+// mutually exclusive ways of sizing a box and combining them would make apportionment ambiguous.
+func (b *Box) validateSizeSpec() {
+	specCount := 0
+	if b.Size > 0 {
+		specCount++
+	}
+	if b.Weight > 0 {
+		specCount++
+	}
+	if b.Percent > 0 {
+		specCount++
+	}
+	if specCount > 1 {
+		panic("Box must set at most one of Size, Weight and Percent")
+	}
+}
+
 func (b *Box) getDirection(width int, height int) int {
 	if b.ConditionalDirection != nil {
 		return b.ConditionalDirection(width, height)
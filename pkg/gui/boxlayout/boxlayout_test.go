@@ -0,0 +1,254 @@
+package boxlayout
+
+import "testing"
+
+func TestArrangeViewsStaticAndWeighted(t *testing.T) {
+	root := &Box{
+		Direction: ROW,
+		Children: []*Box{
+			{ViewName: "a", Size: 2},
+			{ViewName: "b", Weight: 1},
+			{ViewName: "c", Weight: 2},
+		},
+	}
+
+	result := ArrangeViews(root, 0, 0, 10, 10)
+
+	assertHeight(t, result, "a", 2)
+	assertHeight(t, result, "b", 3)
+	assertHeight(t, result, "c", 5)
+}
+
+func TestArrangeViewsMinSizeClampsAndRedistributes(t *testing.T) {
+	root := &Box{
+		Direction: ROW,
+		Children: []*Box{
+			{ViewName: "a", Weight: 1, MinSize: 4},
+			{ViewName: "b", Weight: 1},
+		},
+	}
+
+	// without the MinSize, each would get 3 of the 6 available rows
+	result := ArrangeViews(root, 0, 0, 10, 6)
+
+	assertHeight(t, result, "a", 4)
+	assertHeight(t, result, "b", 2)
+}
+
+func TestArrangeViewsMaxSizeClampsAndRedistributes(t *testing.T) {
+	root := &Box{
+		Direction: ROW,
+		Children: []*Box{
+			{ViewName: "a", Weight: 1, MaxSize: 2},
+			{ViewName: "b", Weight: 1},
+		},
+	}
+
+	result := ArrangeViews(root, 0, 0, 10, 10)
+
+	assertHeight(t, result, "a", 2)
+	assertHeight(t, result, "b", 8)
+}
+
+func TestArrangeViewsMinSizeOverflowsGracefullyWhenSpaceRunsOut(t *testing.T) {
+	root := &Box{
+		Direction: ROW,
+		Children: []*Box{
+			{ViewName: "a", Weight: 1, MinSize: 5},
+			{ViewName: "b", Weight: 1, MinSize: 5},
+		},
+	}
+
+	// only 6 rows for two boxes that each want a minimum of 5
+	result := ArrangeViews(root, 0, 0, 10, 6)
+
+	assertHeight(t, result, "a", 5)
+	assertHeight(t, result, "b", 5)
+}
+
+func TestArrangeViewsGap(t *testing.T) {
+	root := &Box{
+		Direction: ROW,
+		Gap:       1,
+		Children: []*Box{
+			{ViewName: "a", Size: 2},
+			{ViewName: "b", Size: 2},
+		},
+	}
+
+	result := ArrangeViews(root, 0, 0, 10, 10)
+
+	if result["b"].Y0 != result["a"].Y1+2 {
+		t.Fatalf("expected a gap row between a and b, got a=%+v b=%+v", result["a"], result["b"])
+	}
+}
+
+func TestArrangeViewsPadding(t *testing.T) {
+	root := &Box{
+		Direction: ROW,
+		Padding:   2,
+		Children:  []*Box{{ViewName: "a", Weight: 1}},
+	}
+
+	result := ArrangeViews(root, 0, 0, 10, 10)
+
+	if result["a"] != (Dimensions{X0: 2, Y0: 2, X1: 7, Y1: 7}) {
+		t.Fatalf("expected padding to inset the child on all sides, got %+v", result["a"])
+	}
+}
+
+func TestArrangeViewsAlignment(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		alignment int
+		wantY0    int
+	}{
+		{"start", START, 0},
+		{"end", END, 7},
+		{"center", CENTER, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			root := &Box{
+				Direction: ROW,
+				Alignment: tc.alignment,
+				Children:  []*Box{{ViewName: "a", Size: 3}},
+			}
+
+			result := ArrangeViews(root, 0, 0, 10, 10)
+
+			if result["a"].Y0 != tc.wantY0 {
+				t.Fatalf("expected a.Y0 = %d, got %+v", tc.wantY0, result["a"])
+			}
+		})
+	}
+}
+
+func TestArrangeViewsAlignmentAppliesToLeftoverAfterPercent(t *testing.T) {
+	root := &Box{
+		Direction: ROW,
+		Alignment: END,
+		Children: []*Box{
+			{ViewName: "a", Percent: 50},
+		},
+	}
+
+	// 50% of 10 is 5, leaving 5 rows of leftover space that END should push "a" past
+	result := ArrangeViews(root, 0, 0, 10, 10)
+
+	if result["a"].Y0 != 5 {
+		t.Fatalf("expected Alignment to push the percent child past the leftover space, got %+v", result["a"])
+	}
+}
+
+func TestArrangeViewsPercentTakesPriorityOverWeight(t *testing.T) {
+	root := &Box{
+		Direction: ROW,
+		Children: []*Box{
+			{ViewName: "a", Percent: 40},
+			{ViewName: "b", Weight: 1},
+		},
+	}
+
+	result := ArrangeViews(root, 0, 0, 10, 10)
+
+	assertHeight(t, result, "a", 4)
+	assertHeight(t, result, "b", 6)
+}
+
+func TestArrangeViewsPercentIsEvaluatedAfterStaticSize(t *testing.T) {
+	root := &Box{
+		Direction: ROW,
+		Children: []*Box{
+			{ViewName: "a", Size: 2},
+			{ViewName: "b", Percent: 50},
+		},
+	}
+
+	// b's 50% applies to the 8 rows left after a's static size, not the full 10
+	result := ArrangeViews(root, 0, 0, 10, 10)
+
+	assertHeight(t, result, "b", 4)
+}
+
+func TestValidateSizeSpecPanicsOnAmbiguousSizing(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected ArrangeViews to panic when a box sets both Size and Weight")
+		}
+	}()
+
+	root := &Box{
+		Direction: ROW,
+		Children:  []*Box{{ViewName: "a", Size: 2, Weight: 1}},
+	}
+	ArrangeViews(root, 0, 0, 10, 10)
+}
+
+func TestArrangeViewsOverlayAnchors(t *testing.T) {
+	root := &Box{
+		ViewName: "main",
+		OverlayChildren: []*Box{
+			{ViewName: "topLeft", Width: 2, Height: 2, Anchor: AnchorTopLeft},
+			{ViewName: "bottomRight", Width: 2, Height: 2, Anchor: AnchorBottomRight},
+			{ViewName: "center", Width: 2, Height: 2, Anchor: AnchorCenter},
+		},
+	}
+
+	result := ArrangeViews(root, 0, 0, 10, 10)
+
+	if result["topLeft"].X0 != 0 || result["topLeft"].Y0 != 0 {
+		t.Fatalf("expected topLeft at origin, got %+v", result["topLeft"])
+	}
+	if result["bottomRight"].X1 != 9 || result["bottomRight"].Y1 != 9 {
+		t.Fatalf("expected bottomRight flush with the far corner, got %+v", result["bottomRight"])
+	}
+	if result["center"].X0 != 4 || result["center"].Y0 != 4 {
+		t.Fatalf("expected center to be centered, got %+v", result["center"])
+	}
+}
+
+// several sized, Packed overlays (e.g. simultaneous notifications) must not overlap
+func TestArrangeViewsPackedOverlaysDoNotOverlap(t *testing.T) {
+	root := &Box{
+		ViewName: "main",
+		OverlayChildren: []*Box{
+			{ViewName: "n1", Width: 4, Height: 2, Packed: true},
+			{ViewName: "n2", Width: 4, Height: 2, Packed: true},
+			{ViewName: "n3", Width: 4, Height: 2, Packed: true},
+		},
+	}
+
+	result := ArrangeViews(root, 0, 0, 10, 10)
+
+	notifications := []Dimensions{result["n1"], result["n2"], result["n3"]}
+	for i := range notifications {
+		for j := range notifications {
+			if i == j {
+				continue
+			}
+			if rectsOverlap(notifications[i], notifications[j]) {
+				t.Fatalf("expected packed overlays not to overlap, got %+v", notifications)
+			}
+		}
+	}
+
+	// n1 and n2 fit side by side in the first row; n3 wraps onto a second row
+	if result["n3"].Y0 == result["n1"].Y0 {
+		t.Fatalf("expected the packer to wrap onto a new row once the first ran out of width, got %+v", notifications)
+	}
+}
+
+func rectsOverlap(a, b Dimensions) bool {
+	return a.X0 <= b.X1 && b.X0 <= a.X1 && a.Y0 <= b.Y1 && b.Y0 <= a.Y1
+}
+
+func assertHeight(t *testing.T, result map[string]Dimensions, viewName string, want int) {
+	t.Helper()
+	dimensions, ok := result[viewName]
+	if !ok {
+		t.Fatalf("expected a dimensions entry for %q", viewName)
+	}
+	if got := dimensions.Y1 - dimensions.Y0 + 1; got != want {
+		t.Fatalf("expected %q to have height %d, got %d (%+v)", viewName, want, got, dimensions)
+	}
+}